@@ -0,0 +1,32 @@
+package cart
+
+import "errors"
+
+var (
+	ErrCartNotFound      = errors.New("cart not found")
+	ErrItemNotFound      = errors.New("item not found in cart")
+	ErrInsufficientStock = errors.New("insufficient stock")
+	ErrInvalidQuantity   = errors.New("quantity must be positive")
+)
+
+// LineItem is a single product/quantity pair within a cart.
+type LineItem struct {
+	ProductID int `json:"product_id"`
+	Quantity  int `json:"quantity"`
+}
+
+// Item is the GORM-persisted row backing a cart's line items. Carts
+// themselves have no separate table; a cart exists implicitly as long as
+// it has at least one item.
+type Item struct {
+	ID        uint   `gorm:"primaryKey"`
+	CartID    string `gorm:"column:cart_id;uniqueIndex:idx_cart_item"`
+	ProductID int    `gorm:"column:product_id;uniqueIndex:idx_cart_item"`
+	Quantity  int    `gorm:"column:quantity"`
+}
+
+// TableName overrides GORM's pluralized default so the table name stays
+// readable alongside products and outbox_events.
+func (Item) TableName() string {
+	return "cart_items"
+}
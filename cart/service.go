@@ -0,0 +1,146 @@
+package cart
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	productcatalog "repo/product"
+
+	"github.com/segmentio/kafka-go"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Event is published to the cart-events Kafka topic whenever a cart is
+// mutated, so downstream services stay consistent with product-events.
+type Event struct {
+	CartID    string `json:"cart_id"`
+	ProductID int    `json:"product_id,omitempty"`
+	Quantity  int    `json:"quantity,omitempty"`
+	Status    string `json:"status"`
+}
+
+// ItemView is a line item enriched with pricing for display.
+type ItemView struct {
+	ProductID int     `json:"product_id"`
+	Quantity  int     `json:"quantity"`
+	UnitPrice float64 `json:"unit_price"`
+	Subtotal  float64 `json:"subtotal"`
+}
+
+// View is the computed representation of a cart returned to callers.
+type View struct {
+	ID    string     `json:"id"`
+	Items []ItemView `json:"items"`
+	Total float64    `json:"total"`
+}
+
+// Service implements the cart use cases against Postgres (via GORM),
+// validating stock against productcatalog.Product and publishing the
+// resulting events to Kafka.
+type Service struct {
+	db     *gorm.DB
+	writer productcatalog.KafkaWriter
+}
+
+// NewService - Constructor for Service.
+func NewService(db *gorm.DB, writer productcatalog.KafkaWriter) *Service {
+	return &Service{db: db, writer: writer}
+}
+
+// AddItem adds a product to the cart, or updates its quantity if the
+// product is already in the cart. The requested quantity must not exceed
+// the product's current stock.
+func (s *Service) AddItem(ctx context.Context, cartID string, productID, quantity int) error {
+	if quantity <= 0 {
+		return ErrInvalidQuantity
+	}
+
+	var p productcatalog.Product
+	if err := s.db.Where("id = ?", productID).First(&p).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return productcatalog.ErrProductNotFound
+		}
+		return err
+	}
+	if p.Quantity < quantity {
+		return ErrInsufficientStock
+	}
+
+	item := Item{CartID: cartID, ProductID: productID, Quantity: quantity}
+	err := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "cart_id"}, {Name: "product_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"quantity"}),
+	}).Create(&item).Error
+	if err != nil {
+		return err
+	}
+
+	return s.publish(ctx, Event{CartID: cartID, ProductID: productID, Quantity: quantity, Status: "item_added"})
+}
+
+// RemoveItem removes a product from the cart. If the cart has no items
+// left afterwards, a cart_cleared event is published alongside item_removed.
+func (s *Service) RemoveItem(ctx context.Context, cartID string, productID int) error {
+	res := s.db.Where("cart_id = ? AND product_id = ?", cartID, productID).Delete(&Item{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrItemNotFound
+	}
+
+	if err := s.publish(ctx, Event{CartID: cartID, ProductID: productID, Status: "item_removed"}); err != nil {
+		return err
+	}
+
+	var remaining int64
+	if err := s.db.Model(&Item{}).Where("cart_id = ?", cartID).Count(&remaining).Error; err != nil {
+		return err
+	}
+	if remaining == 0 {
+		return s.publish(ctx, Event{CartID: cartID, Status: "cart_cleared"})
+	}
+	return nil
+}
+
+// GetCart returns the cart's line items enriched with current product
+// pricing and the computed cart total.
+func (s *Service) GetCart(ctx context.Context, cartID string) (*View, error) {
+	var items []Item
+	if err := s.db.Where("cart_id = ?", cartID).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, ErrCartNotFound
+	}
+
+	view := &View{ID: cartID, Items: make([]ItemView, 0, len(items))}
+	for _, item := range items {
+		var p productcatalog.Product
+		if err := s.db.Where("id = ?", item.ProductID).First(&p).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		subtotal := p.Price * float64(item.Quantity)
+		view.Items = append(view.Items, ItemView{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			UnitPrice: p.Price,
+			Subtotal:  subtotal,
+		})
+		view.Total += subtotal
+	}
+	return view, nil
+}
+
+func (s *Service) publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return s.writer.WriteMessages(ctx, kafka.Message{Value: payload})
+}
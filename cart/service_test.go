@@ -0,0 +1,113 @@
+package cart
+
+import (
+	"context"
+	"testing"
+
+	productcatalog "repo/product"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// fakeWriter records every message it's asked to write, instead of
+// requiring a live Kafka broker.
+type fakeWriter struct {
+	messages []kafka.Message
+}
+
+func (w *fakeWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	w.messages = append(w.messages, msgs...)
+	return nil
+}
+
+func newTestCartService(t *testing.T) (*Service, *fakeWriter) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&productcatalog.Product{}, &Item{}))
+	require.NoError(t, db.Create(&productcatalog.Product{ID: 1, Name: "Widget", Price: 5, Quantity: 10}).Error)
+
+	writer := &fakeWriter{}
+	return NewService(db, writer), writer
+}
+
+func TestService_AddItem_InsufficientStock(t *testing.T) {
+	svc, _ := newTestCartService(t)
+
+	err := svc.AddItem(context.Background(), "cart-1", 1, 11)
+	assert.ErrorIs(t, err, ErrInsufficientStock)
+}
+
+func TestService_AddItem_ProductNotFound(t *testing.T) {
+	svc, _ := newTestCartService(t)
+
+	err := svc.AddItem(context.Background(), "cart-1", 999, 1)
+	assert.ErrorIs(t, err, productcatalog.ErrProductNotFound)
+}
+
+func TestService_AddItem_RejectsNonPositiveQuantity(t *testing.T) {
+	svc, _ := newTestCartService(t)
+	ctx := context.Background()
+
+	assert.ErrorIs(t, svc.AddItem(ctx, "cart-1", 1, -3), ErrInvalidQuantity)
+	assert.ErrorIs(t, svc.AddItem(ctx, "cart-1", 1, 0), ErrInvalidQuantity)
+
+	// A negative quantity must never be stored, or it would later
+	// discount GetCart's computed total.
+	_, err := svc.GetCart(ctx, "cart-1")
+	assert.ErrorIs(t, err, ErrCartNotFound)
+}
+
+func TestService_AddItem_UpsertsQuantity(t *testing.T) {
+	svc, writer := newTestCartService(t)
+	ctx := context.Background()
+
+	require.NoError(t, svc.AddItem(ctx, "cart-1", 1, 2))
+	require.NoError(t, svc.AddItem(ctx, "cart-1", 1, 5))
+
+	view, err := svc.GetCart(ctx, "cart-1")
+	require.NoError(t, err)
+	require.Len(t, view.Items, 1)
+	assert.Equal(t, 5, view.Items[0].Quantity)
+	assert.Len(t, writer.messages, 2)
+}
+
+func TestService_RemoveItem_PublishesCartCleared(t *testing.T) {
+	svc, writer := newTestCartService(t)
+	ctx := context.Background()
+
+	require.NoError(t, svc.AddItem(ctx, "cart-1", 1, 2))
+	require.NoError(t, svc.RemoveItem(ctx, "cart-1", 1))
+
+	_, err := svc.GetCart(ctx, "cart-1")
+	assert.ErrorIs(t, err, ErrCartNotFound)
+
+	var statuses []string
+	for _, msg := range writer.messages {
+		statuses = append(statuses, string(msg.Value))
+	}
+	assert.Contains(t, statuses[len(statuses)-1], "cart_cleared")
+}
+
+func TestService_RemoveItem_NotFound(t *testing.T) {
+	svc, _ := newTestCartService(t)
+
+	err := svc.RemoveItem(context.Background(), "cart-1", 1)
+	assert.ErrorIs(t, err, ErrItemNotFound)
+}
+
+func TestService_GetCart_ComputesTotal(t *testing.T) {
+	svc, _ := newTestCartService(t)
+	ctx := context.Background()
+
+	require.NoError(t, svc.AddItem(ctx, "cart-1", 1, 3))
+
+	view, err := svc.GetCart(ctx, "cart-1")
+	require.NoError(t, err)
+	assert.Equal(t, 15.0, view.Total)
+}
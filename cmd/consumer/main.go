@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	productcatalog "repo/product"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	// Load environment variables from .env file
+	if err := godotenv.Load(); err != nil {
+		panic("Error loading .env file")
+	}
+
+	brokers := strings.Split(os.Getenv("KAFKA_BROKER"), ",")
+	topic := os.Getenv("KAFKA_CONSUMER_TOPIC")
+	if topic == "" {
+		topic = "product-events"
+	}
+	groupID := os.Getenv("KAFKA_CONSUMER_GROUP")
+	if groupID == "" {
+		groupID = "productcatalog-consumer"
+	}
+
+	consumer := productcatalog.NewConsumer(brokers, topic, groupID)
+	defer consumer.Close()
+
+	// Demo audit-log handler: records every product mutation.
+	for _, eventType := range []string{"created", "updated", "deleted", "consumed"} {
+		eventType := eventType
+		consumer.Register(eventType, func(ctx context.Context, eventType string, payload []byte) error {
+			log.Printf("audit: %s %s", eventType, payload)
+			return nil
+		})
+	}
+
+	// Demo cache-invalidation handler: in a multi-instance deployment this
+	// would drop the local in-process cache entry for the product.
+	consumer.Register("cache_invalidation", func(ctx context.Context, eventType string, payload []byte) error {
+		log.Printf("cache invalidation: %s", payload)
+		return nil
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := consumer.Run(ctx); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"net"
+	"os"
+	"time"
+
+	productcatalog "repo/product"
+	"repo/product/productpb"
+
+	"github.com/joho/godotenv"
+	"github.com/segmentio/kafka-go"
+	"google.golang.org/grpc"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func main() {
+	// Load environment variables from .env file
+	err := godotenv.Load()
+	if err != nil {
+		panic("Error loading .env file")
+	}
+
+	// Initialize PostgreSQL connection
+	dsn := "host=" + os.Getenv("DB_HOST") +
+		" user=" + os.Getenv("DB_USER") +
+		" password=" + os.Getenv("DB_PASSWORD") +
+		" dbname=" + os.Getenv("DB_NAME") +
+		" port=" + os.Getenv("DB_PORT") +
+		" sslmode=disable TimeZone=Asia/Shanghai"
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		panic("failed to connect to database")
+	}
+
+	// Auto migrate the Product and outbox models
+	db.AutoMigrate(&productcatalog.Product{}, &productcatalog.OutboxEvent{})
+
+	// Initialize Kafka writer and the outbox publisher that drains it
+	kafkaWriter := &kafka.Writer{
+		Addr:     kafka.TCP(os.Getenv("KAFKA_BROKER")),
+		Topic:    "product-events",
+		Balancer: &kafka.LeastBytes{},
+	}
+	defer kafkaWriter.Close()
+	outboxPublisher := productcatalog.NewOutboxPublisher(db, kafkaWriter)
+	go outboxPublisher.Run(context.Background(), 5*time.Second)
+
+	svc := productcatalog.NewService(db)
+
+	lis, err := net.Listen("tcp", ":"+os.Getenv("GRPC_PORT"))
+	if err != nil {
+		panic("failed to listen on gRPC port")
+	}
+
+	grpcServer := grpc.NewServer()
+	productpb.RegisterProductCatalogServer(grpcServer, productcatalog.NewGRPCServer(svc))
+
+	if err := grpcServer.Serve(lis); err != nil {
+		panic("failed to serve gRPC")
+	}
+}
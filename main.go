@@ -2,32 +2,34 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"os"
 	"strconv"
+	"time"
 
+	"repo/cart"
 	productcatalog "repo/product"
+	"repo/publish"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"github.com/redis/go-redis/v9"
 	"github.com/segmentio/kafka-go"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-type OrderCreated struct {
-	OrderID   int    `json:"order_id"`
-	ProductID int    `json:"product_id"`
-	Quantity  int    `json:"quantity"`
-	Status    string `json:"status"`
-}
-
 var (
-	db          *gorm.DB
-	kafkaWriter *kafka.Writer
-	inventory   = productcatalog.Inventory{Products: make(map[int]productcatalog.Product)}
+	db           *gorm.DB
+	kafkaWriter  *kafka.Writer
+	cartService  *cart.Service
+	reservations *productcatalog.ReservationStore
+	productCache productcatalog.ProductCache
+	productStore *productcatalog.GormStorage
+	productSvc   *productcatalog.Service
+	versions     *publish.Service
 )
 
 func main() {
@@ -49,15 +51,53 @@ func main() {
 		panic("failed to connect to database")
 	}
 
-	// Auto migrate the Product model
-	db.AutoMigrate(&productcatalog.Product{})
+	// Auto migrate the Product and outbox models
+	db.AutoMigrate(&productcatalog.Product{}, &productcatalog.OutboxEvent{})
 
-	// Initialize Kafka writer
+	// Initialize Kafka writer and the outbox publisher that drains it
 	kafkaWriter = &kafka.Writer{
 		Addr:     kafka.TCP(os.Getenv("KAFKA_BROKER")),
 		Topic:    "product-events",
 		Balancer: &kafka.LeastBytes{},
 	}
+	outboxPublisher := productcatalog.NewOutboxPublisher(db, kafkaWriter)
+	go outboxPublisher.Run(context.Background(), 5*time.Second)
+
+	// Auto migrate the Cart model and initialize its Kafka writer
+	db.AutoMigrate(&cart.Item{})
+	cartKafkaWriter := &kafka.Writer{
+		Addr:     kafka.TCP(os.Getenv("KAFKA_BROKER")),
+		Topic:    "cart-events",
+		Balancer: &kafka.LeastBytes{},
+	}
+	cartService = cart.NewService(db, cartKafkaWriter)
+
+	// Initialize the product cache: Redis-backed when REDIS_ADDR is set,
+	// otherwise a NullCache that talks straight to Postgres. This needs to
+	// exist before the reservation store and version scheduler below,
+	// since both invalidate it whenever they mutate a Product row directly.
+	productStore = productcatalog.NewGormStorage(db)
+	if redisAddr := os.Getenv("REDIS_ADDR"); redisAddr != "" {
+		redisClient := redis.NewClient(&redis.Options{Addr: redisAddr})
+		ttl := productcatalog.DefaultCacheTTL
+		if seconds, err := strconv.Atoi(os.Getenv("REDIS_CACHE_TTL_SECONDS")); err == nil {
+			ttl = time.Duration(seconds) * time.Second
+		}
+		productCache = productcatalog.NewRedisCache(productStore, redisClient, kafkaWriter, ttl)
+	} else {
+		productCache = productcatalog.NewNullCache(productStore)
+	}
+	productSvc = productcatalog.NewService(db)
+
+	// Auto migrate the Reservation model and start the expiry sweeper
+	db.AutoMigrate(&productcatalog.Reservation{})
+	reservations = productcatalog.NewReservationStore(db, kafkaWriter, productCache)
+	go reservations.RunSweeper(context.Background(), 30*time.Second)
+
+	// Auto migrate the Version model and start the publish scheduler
+	db.AutoMigrate(&publish.Version{})
+	versions = publish.NewService(db, kafkaWriter, productCache)
+	go versions.RunScheduler(context.Background(), 30*time.Second)
 
 	// Initialize Gin router
 	router := gin.Default()
@@ -69,6 +109,22 @@ func main() {
 	router.DELETE("/products/:id", deleteProduct)
 	router.GET("/products", listProducts)
 
+	// Define cart routes
+	router.POST("/carts/:id/items", addCartItem)
+	router.DELETE("/carts/:id/items/:productID", removeCartItem)
+	router.GET("/carts/:id", getCart)
+
+	// Define reservation routes
+	router.POST("/products/:id/reserve", reserveProduct)
+	router.POST("/products/:id/commit", commitReservation)
+	router.POST("/products/:id/release", releaseReservation)
+	router.POST("/products/:id/consume", consumeProduct)
+
+	// Define product version routes
+	router.POST("/products/:id/versions", createProductVersion)
+	router.POST("/products/:id/versions/:version/publish", publishProductVersion)
+	router.POST("/products/:id/versions/:version/unpublish", unpublishProductVersion)
+
 	// Run the server
 	router.Run(":8080")
 }
@@ -80,25 +136,27 @@ func createProduct(c *gin.Context) {
 		return
 	}
 
-	// Check if a product with the same ID already exists
-	var existingProduct productcatalog.Product
-	if err := db.Where("id = ?", product.ID).First(&existingProduct).Error; err == nil {
-		// Product with the same ID already exists
-		c.JSON(http.StatusConflict, gin.H{"error": "Product with the same ID already exists"})
-		return
-	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
-		// Some other error occurred
+	// Create the product and enqueue its "created" event in the same
+	// transaction via the outbox-backed Service, rather than writing to
+	// Kafka directly, so a crash between the two can't silently drop it.
+	created, err := productSvc.CreateProduct(c.Request.Context(), product)
+	if err != nil {
+		if errors.Is(err, productcatalog.ErrProductAlreadyExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Product with the same ID already exists"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Create the new product
-	if err := db.Create(&product).Error; err != nil {
+	// Drop any stale cache entry for this ID (e.g. a prior delete that a
+	// NullCache-less instance still has cached) now that it exists again.
+	if err := productCache.Invalidate(created.ID); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, product)
+	c.JSON(http.StatusOK, created)
 }
 
 func getProduct(c *gin.Context) {
@@ -107,9 +165,28 @@ func getProduct(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
 		return
 	}
-	product, exists := inventory.Products[id]
-	if !exists {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+
+	if versionName := c.Query("version"); versionName != "" {
+		version, err := versions.GetVersion(id, versionName)
+		if err != nil {
+			if errors.Is(err, publish.ErrVersionNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, version)
+		return
+	}
+
+	product, err := productCache.GetByID(id)
+	if err != nil {
+		if errors.Is(err, productcatalog.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, product)
@@ -126,94 +203,328 @@ func updateProduct(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	product, exists := inventory.Products[id]
-	if !exists {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+
+	// Update the product and enqueue its "updated" event in the same
+	// transaction via the outbox-backed Service, rather than the previous
+	// separate db.Save + kafkaWriter.WriteMessages, where a crash between
+	// the two would silently lose the event.
+	product, err := productSvc.UpdateProduct(c.Request.Context(), id, updatedProduct)
+	if err != nil {
+		if errors.Is(err, productcatalog.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Drop the now-stale cache entry so the next read repopulates it.
+	if err := productCache.Invalidate(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, product)
+}
+
+func deleteProduct(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	// Delete the product and enqueue its "deleted" event in the same
+	// transaction via the outbox-backed Service; see updateProduct.
+	if err := productSvc.DeleteProduct(c.Request.Context(), id); err != nil {
+		if errors.Is(err, productcatalog.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	product.Name = updatedProduct.Name
-	product.Price = updatedProduct.Price
-	product.Quantity = updatedProduct.Quantity
-	product.Category = updatedProduct.Category
-	inventory.Products[id] = product
 
-	// Update the product in the database
-	if err := db.Save(&product).Error; err != nil {
+	// Drop the now-stale cache entry.
+	if err := productCache.Invalidate(id); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Publish event to Kafka
-	orderCreated := OrderCreated{
-		OrderID:   id,
-		ProductID: id,
-		Quantity:  product.Quantity,
-		Status:    "updated",
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func listProducts(c *gin.Context) {
+	products, err := productCache.List()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
-	orderCreatedJSON, err := json.Marshal(orderCreated)
+	c.JSON(http.StatusOK, products)
+}
+
+func addCartItem(c *gin.Context) {
+	cartID := c.Param("id")
+	var body struct {
+		ProductID int `json:"product_id"`
+		Quantity  int `json:"quantity"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := cartService.AddItem(c.Request.Context(), cartID, body.ProductID, body.Quantity); err != nil {
+		switch {
+		case errors.Is(err, cart.ErrInvalidQuantity):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, cart.ErrInsufficientStock):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, productcatalog.ErrProductNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"cart_id": cartID, "product_id": body.ProductID, "quantity": body.Quantity})
+}
+
+func removeCartItem(c *gin.Context) {
+	cartID := c.Param("id")
+	productID, err := strconv.Atoi(c.Param("productID"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to serialize order event"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	if err := cartService.RemoveItem(c.Request.Context(), cartID, productID); err != nil {
+		if errors.Is(err, cart.ErrItemNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	err = kafkaWriter.WriteMessages(context.Background(), kafka.Message{
-		Value: orderCreatedJSON,
-	})
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func getCart(c *gin.Context) {
+	cartID := c.Param("id")
+
+	view, err := cartService.GetCart(c.Request.Context(), cartID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to publish order event"})
+		if errors.Is(err, cart.ErrCartNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, product)
+	c.JSON(http.StatusOK, view)
 }
 
-func deleteProduct(c *gin.Context) {
+func reserveProduct(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var body struct {
+		Quantity   int    `json:"quantity"`
+		HoldSecret string `json:"hold_secret"`
+		TTLSeconds int    `json:"ttl_seconds"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	reservation, err := reservations.Reserve(id, body.Quantity, body.HoldSecret, time.Duration(body.TTLSeconds)*time.Second)
+	if err != nil {
+		switch {
+		case errors.Is(err, productcatalog.ErrInvalidQuantity):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, productcatalog.ErrInsufficientStock):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, productcatalog.ErrProductNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, reservation)
+}
+
+func commitReservation(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
 		return
 	}
-	_, exists := inventory.Products[id]
-	if !exists {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+
+	var body struct {
+		HoldSecret string `json:"hold_secret"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	delete(inventory.Products, id)
 
-	// Delete the product from the database
-	if err := db.Delete(&productcatalog.Product{}, id).Error; err != nil {
+	if err := reservations.Commit(c.Request.Context(), id, body.HoldSecret); err != nil {
+		if errors.Is(err, productcatalog.ErrReservationNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Publish event to Kafka
-	orderCreated := OrderCreated{
-		OrderID:   id,
-		ProductID: id,
-		Quantity:  0,
-		Status:    "deleted",
+	c.JSON(http.StatusOK, gin.H{"status": "committed"})
+}
+
+func releaseReservation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var body struct {
+		HoldSecret string `json:"hold_secret"`
 	}
-	orderCreatedJSON, err := json.Marshal(orderCreated)
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := reservations.Release(id, body.HoldSecret); err != nil {
+		if errors.Is(err, productcatalog.ErrReservationNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "released"})
+}
+
+func consumeProduct(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+	quantity, err := strconv.Atoi(c.Query("quantity"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to serialize order event"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid quantity"})
 		return
 	}
 
-	err = kafkaWriter.WriteMessages(context.Background(), kafka.Message{
-		Value: orderCreatedJSON,
-	})
+	product, err := productSvc.Consume(c.Request.Context(), id, quantity)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to publish order event"})
+		switch {
+		case errors.Is(err, productcatalog.ErrInvalidQuantity):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, productcatalog.ErrInsufficientStock):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, productcatalog.ErrProductNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
-	c.JSON(http.StatusNoContent, nil)
+	c.JSON(http.StatusOK, product)
 }
 
-func listProducts(c *gin.Context) {
-	products := make([]productcatalog.Product, 0, len(inventory.Products))
-	for _, product := range inventory.Products {
-		products = append(products, product)
+func createProductVersion(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
 	}
-	c.JSON(http.StatusOK, products)
+
+	var body struct {
+		VersionName string `json:"version_name"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	version, err := versions.CreateDraft(id, body.VersionName)
+	if err != nil {
+		if errors.Is(err, productcatalog.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, version)
+}
+
+func publishProductVersion(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+	versionName := c.Param("version")
+
+	var body struct {
+		ScheduledStart *time.Time `json:"scheduled_start"`
+		ScheduledEnd   *time.Time `json:"scheduled_end"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := versions.Publish(c.Request.Context(), id, versionName, body.ScheduledStart, body.ScheduledEnd); err != nil {
+		switch {
+		case errors.Is(err, publish.ErrVersionNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, publish.ErrVersionNotDraft):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+func unpublishProductVersion(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+	versionName := c.Param("version")
+
+	if err := versions.Unpublish(c.Request.Context(), id, versionName); err != nil {
+		switch {
+		case errors.Is(err, publish.ErrVersionNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, publish.ErrVersionNotOnline):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
 }
@@ -0,0 +1,155 @@
+package product
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/segmentio/kafka-go"
+)
+
+// DefaultCacheTTL is used when a non-positive TTL is passed to NewRedisCache.
+const DefaultCacheTTL = time.Hour
+
+// ProductCache is a ProductStorage that may additionally cache reads. It is
+// the same interface so callers can swap a RedisCache in for a NullCache
+// without changing any handler code. Invalidate is exposed separately so
+// callers that mutate a product through a path other than Save/Delete
+// (e.g. the outbox-backed Service, ReservationStore, or the publish package)
+// can still drop the stale cache entry without going through a second,
+// redundant storage write.
+type ProductCache interface {
+	ProductStorage
+	Invalidate(id int) error
+}
+
+// listCacheKey caches the full product list as a single Redis entry, since
+// there's no per-ID key that makes sense for a collection read.
+const listCacheKey = "products:all"
+
+// NullCache is a no-op ProductCache for deployments that don't run Redis;
+// it delegates every call straight through to the wrapped storage.
+type NullCache struct {
+	ProductStorage
+}
+
+// NewNullCache - Constructor for NullCache.
+func NewNullCache(storage ProductStorage) *NullCache {
+	return &NullCache{ProductStorage: storage}
+}
+
+// Invalidate is a no-op: there is no cache to drop an entry from.
+func (n *NullCache) Invalidate(id int) error {
+	return nil
+}
+
+// RedisCache wraps a ProductStorage with a Redis read-through cache keyed
+// by product ID. Reads check Redis first and repopulate it on miss; writes
+// invalidate the key and publish an invalidation event so other instances
+// drop their own copy.
+type RedisCache struct {
+	storage ProductStorage
+	client  *redis.Client
+	writer  KafkaWriter
+	ttl     time.Duration
+}
+
+// CacheInvalidation is published whenever a cached product is written or
+// deleted, so other instances know to drop their local copy.
+type CacheInvalidation struct {
+	ProductID int `json:"product_id"`
+}
+
+// NewRedisCache - Constructor for RedisCache.
+func NewRedisCache(storage ProductStorage, client *redis.Client, writer KafkaWriter, ttl time.Duration) *RedisCache {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &RedisCache{storage: storage, client: client, writer: writer, ttl: ttl}
+}
+
+func cacheKey(id int) string {
+	return fmt.Sprintf("product:%d", id)
+}
+
+// GetByID checks Redis first, falling back to the wrapped storage and
+// repopulating the cache on a miss.
+func (r *RedisCache) GetByID(id int) (*Product, error) {
+	ctx := context.Background()
+
+	if raw, err := r.client.Get(ctx, cacheKey(id)).Bytes(); err == nil {
+		var p Product
+		if err := json.Unmarshal(raw, &p); err == nil {
+			return &p, nil
+		}
+	}
+
+	p, err := r.storage.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if payload, err := json.Marshal(p); err == nil {
+		r.client.Set(ctx, cacheKey(id), payload, r.ttl)
+	}
+	return p, nil
+}
+
+// List checks Redis first, falling back to the wrapped storage and
+// repopulating the cache on a miss.
+func (r *RedisCache) List() ([]Product, error) {
+	ctx := context.Background()
+
+	if raw, err := r.client.Get(ctx, listCacheKey).Bytes(); err == nil {
+		var products []Product
+		if err := json.Unmarshal(raw, &products); err == nil {
+			return products, nil
+		}
+	}
+
+	products, err := r.storage.List()
+	if err != nil {
+		return nil, err
+	}
+
+	if payload, err := json.Marshal(products); err == nil {
+		r.client.Set(ctx, listCacheKey, payload, r.ttl)
+	}
+	return products, nil
+}
+
+// Save writes through to the wrapped storage, then invalidates the cache.
+func (r *RedisCache) Save(p Product) error {
+	if err := r.storage.Save(p); err != nil {
+		return err
+	}
+	return r.Invalidate(p.ID)
+}
+
+// Delete deletes from the wrapped storage, then invalidates the cache.
+func (r *RedisCache) Delete(id int) error {
+	if err := r.storage.Delete(id); err != nil {
+		return err
+	}
+	return r.Invalidate(id)
+}
+
+// Invalidate drops the cached entry for id (and the cached product list,
+// which may now be stale too) and publishes a CacheInvalidation event, for
+// callers that mutated the product through a path other than Save/Delete
+// (e.g. Service's outbox-backed writes, ReservationStore, or the publish
+// package) and so never went through this cache.
+func (r *RedisCache) Invalidate(id int) error {
+	ctx := context.Background()
+	if err := r.client.Del(ctx, cacheKey(id), listCacheKey).Err(); err != nil && err != redis.Nil {
+		return err
+	}
+
+	payload, err := json.Marshal(CacheInvalidation{ProductID: id})
+	if err != nil {
+		return err
+	}
+	return r.writer.WriteMessages(ctx, kafka.Message{Key: []byte("cache_invalidation"), Value: payload})
+}
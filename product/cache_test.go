@@ -0,0 +1,102 @@
+package product
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newTestRedisCache(t *testing.T) (*RedisCache, *GormStorage, *fakeWriter) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&Product{}))
+	storage := NewGormStorage(db)
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	writer := &fakeWriter{}
+	return NewRedisCache(storage, client, writer, time.Minute), storage, writer
+}
+
+func TestRedisCache_GetByID_PopulatesOnMiss(t *testing.T) {
+	cache, storage, _ := newTestRedisCache(t)
+	require.NoError(t, storage.Create(Product{ID: 1, Name: "Widget"}))
+
+	got, err := cache.GetByID(1)
+	require.NoError(t, err)
+	assert.Equal(t, "Widget", got.Name)
+
+	// Change the underlying row directly; a cached read should still see
+	// the stale value, proving the first GetByID populated the cache.
+	require.NoError(t, storage.Save(Product{ID: 1, Name: "Widget v2"}))
+	got, err = cache.GetByID(1)
+	require.NoError(t, err)
+	assert.Equal(t, "Widget", got.Name)
+}
+
+func TestRedisCache_List_PopulatesOnMiss(t *testing.T) {
+	cache, storage, _ := newTestRedisCache(t)
+	require.NoError(t, storage.Create(Product{ID: 1, Name: "Widget"}))
+
+	products, err := cache.List()
+	require.NoError(t, err)
+	assert.Len(t, products, 1)
+
+	require.NoError(t, storage.Create(Product{ID: 2, Name: "Gadget"}))
+	products, err = cache.List()
+	require.NoError(t, err)
+	assert.Len(t, products, 1, "stale cached list should still be served")
+}
+
+func TestRedisCache_Save_InvalidatesEntryAndList(t *testing.T) {
+	cache, storage, writer := newTestRedisCache(t)
+	require.NoError(t, storage.Create(Product{ID: 1, Name: "Widget"}))
+	_, err := cache.GetByID(1)
+	require.NoError(t, err)
+	_, err = cache.List()
+	require.NoError(t, err)
+
+	require.NoError(t, cache.Save(Product{ID: 1, Name: "Widget v2"}))
+
+	got, err := cache.GetByID(1)
+	require.NoError(t, err)
+	assert.Equal(t, "Widget v2", got.Name)
+
+	products, err := cache.List()
+	require.NoError(t, err)
+	require.Len(t, products, 1)
+	assert.Equal(t, "Widget v2", products[0].Name)
+
+	assert.Len(t, writer.messages, 1)
+	assert.Equal(t, "cache_invalidation", string(writer.messages[0].Key))
+}
+
+func TestRedisCache_Delete_InvalidatesEntry(t *testing.T) {
+	cache, storage, _ := newTestRedisCache(t)
+	require.NoError(t, storage.Create(Product{ID: 1, Name: "Widget"}))
+	_, err := cache.GetByID(1)
+	require.NoError(t, err)
+
+	require.NoError(t, cache.Delete(1))
+
+	_, err = cache.GetByID(1)
+	assert.ErrorIs(t, err, ErrProductNotFound)
+}
+
+func TestNullCache_Invalidate_IsNoop(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&Product{}))
+
+	cache := NewNullCache(NewGormStorage(db))
+	assert.NoError(t, cache.Invalidate(1))
+}
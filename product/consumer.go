@@ -0,0 +1,74 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// MessageHandler processes a single product-events message. eventType is
+// the Kafka message key (e.g. "created", "consumed", "cache_invalidation");
+// payload is the raw message value.
+type MessageHandler func(ctx context.Context, eventType string, payload []byte) error
+
+// Consumer reads product-events from Kafka and routes each message to the
+// handler registered for its event type, so a single topic can fan out to
+// unrelated concerns (cache invalidation, audit logging, ...) without those
+// concerns depending on the publisher that wrote the message.
+type Consumer struct {
+	reader   *kafka.Reader
+	handlers map[string]MessageHandler
+}
+
+// NewConsumer - Constructor for Consumer.
+func NewConsumer(brokers []string, topic, groupID string) *Consumer {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: brokers,
+		Topic:   topic,
+		GroupID: groupID,
+	})
+	return &Consumer{reader: reader, handlers: make(map[string]MessageHandler)}
+}
+
+// Register associates a handler with an event type. Messages whose key
+// doesn't match a registered event type are skipped.
+func (c *Consumer) Register(eventType string, handler MessageHandler) {
+	c.handlers[eventType] = handler
+}
+
+// Run reads messages until ctx is cancelled, dispatching each to its
+// registered handler. A handler error is logged rather than stopping the
+// consumer, so one bad message can't wedge the whole stream.
+func (c *Consumer) Run(ctx context.Context) error {
+	for {
+		msg, err := c.reader.ReadMessage(ctx)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return nil
+			}
+			return err
+		}
+
+		c.dispatch(ctx, string(msg.Key), msg.Value)
+	}
+}
+
+// dispatch routes a single message to its registered handler, logging
+// rather than propagating a handler error so Run doesn't need to. Messages
+// whose key matches no registered event type are skipped.
+func (c *Consumer) dispatch(ctx context.Context, eventType string, payload []byte) {
+	handler, ok := c.handlers[eventType]
+	if !ok {
+		return
+	}
+	if err := handler(ctx, eventType, payload); err != nil {
+		log.Printf("consumer: handler for %q failed: %v", eventType, err)
+	}
+}
+
+// Close closes the underlying Kafka reader.
+func (c *Consumer) Close() error {
+	return c.reader.Close()
+}
@@ -0,0 +1,52 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsumer_Dispatch_RoutesToRegisteredHandler(t *testing.T) {
+	c := &Consumer{handlers: make(map[string]MessageHandler)}
+
+	var gotType string
+	var gotPayload []byte
+	c.Register("created", func(_ context.Context, eventType string, payload []byte) error {
+		gotType = eventType
+		gotPayload = payload
+		return nil
+	})
+
+	c.dispatch(context.Background(), "created", []byte(`{"id":1}`))
+
+	assert.Equal(t, "created", gotType)
+	assert.Equal(t, `{"id":1}`, string(gotPayload))
+}
+
+func TestConsumer_Dispatch_SkipsUnregisteredEventType(t *testing.T) {
+	c := &Consumer{handlers: make(map[string]MessageHandler)}
+
+	called := false
+	c.Register("created", func(context.Context, string, []byte) error {
+		called = true
+		return nil
+	})
+
+	c.dispatch(context.Background(), "deleted", []byte(`{}`))
+
+	assert.False(t, called)
+}
+
+func TestConsumer_Dispatch_HandlerErrorDoesNotPanic(t *testing.T) {
+	c := &Consumer{handlers: make(map[string]MessageHandler)}
+
+	c.Register("created", func(context.Context, string, []byte) error {
+		return errors.New("boom")
+	})
+
+	assert.NotPanics(t, func() {
+		c.dispatch(context.Background(), "created", []byte(`{}`))
+	})
+}
@@ -0,0 +1,60 @@
+package product
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// GormStorage implements ProductStorage against Postgres via GORM.
+type GormStorage struct {
+	db *gorm.DB
+}
+
+// NewGormStorage - Constructor for GormStorage.
+func NewGormStorage(db *gorm.DB) *GormStorage {
+	return &GormStorage{db: db}
+}
+
+// Create method - Inserts a new product into Postgres.
+func (g *GormStorage) Create(p Product) error {
+	return g.db.Create(&p).Error
+}
+
+// Save method - Updates an existing product in Postgres.
+func (g *GormStorage) Save(p Product) error {
+	return g.db.Save(&p).Error
+}
+
+// GetByID method - Retrieves a product by ID from Postgres.
+func (g *GormStorage) GetByID(id int) (*Product, error) {
+	var p Product
+	if err := g.db.Where("id = ?", id).First(&p).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Delete method - Deletes a product by ID from Postgres.
+func (g *GormStorage) Delete(id int) error {
+	res := g.db.Delete(&Product{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrProductNotFound
+	}
+	return nil
+}
+
+// List method - Returns every product in Postgres.
+func (g *GormStorage) List() ([]Product, error) {
+	var products []Product
+	if err := g.db.Find(&products).Error; err != nil {
+		return nil, err
+	}
+	return products, nil
+}
@@ -0,0 +1,72 @@
+package product
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newTestGormStorage(t *testing.T) *GormStorage {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&Product{}))
+
+	return NewGormStorage(db)
+}
+
+func TestGormStorage_Create_InsertsNewRow(t *testing.T) {
+	storage := newTestGormStorage(t)
+
+	require.NoError(t, storage.Create(Product{ID: 1, Name: "Widget", Price: 9.99, Quantity: 5}))
+
+	got, err := storage.GetByID(1)
+	require.NoError(t, err)
+	assert.Equal(t, "Widget", got.Name)
+}
+
+func TestGormStorage_Save_UpdatesExistingRow(t *testing.T) {
+	storage := newTestGormStorage(t)
+	require.NoError(t, storage.Create(Product{ID: 1, Name: "Widget", Price: 9.99, Quantity: 5}))
+
+	require.NoError(t, storage.Save(Product{ID: 1, Name: "Widget v2", Price: 12.50, Quantity: 3}))
+
+	got, err := storage.GetByID(1)
+	require.NoError(t, err)
+	assert.Equal(t, "Widget v2", got.Name)
+	assert.Equal(t, 3, got.Quantity)
+}
+
+func TestGormStorage_GetByID_NotFound(t *testing.T) {
+	storage := newTestGormStorage(t)
+
+	_, err := storage.GetByID(999)
+	assert.ErrorIs(t, err, ErrProductNotFound)
+}
+
+func TestGormStorage_Delete(t *testing.T) {
+	storage := newTestGormStorage(t)
+	require.NoError(t, storage.Create(Product{ID: 1, Name: "Widget"}))
+
+	require.NoError(t, storage.Delete(1))
+
+	_, err := storage.GetByID(1)
+	assert.ErrorIs(t, err, ErrProductNotFound)
+
+	err = storage.Delete(1)
+	assert.ErrorIs(t, err, ErrProductNotFound)
+}
+
+func TestGormStorage_List(t *testing.T) {
+	storage := newTestGormStorage(t)
+	require.NoError(t, storage.Create(Product{ID: 1, Name: "Widget"}))
+	require.NoError(t, storage.Create(Product{ID: 2, Name: "Gadget"}))
+
+	products, err := storage.List()
+	require.NoError(t, err)
+	assert.Len(t, products, 2)
+}
@@ -0,0 +1,101 @@
+package product
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"repo/product/productpb"
+)
+
+// GRPCServer adapts Service to the generated ProductCatalogServer interface.
+type GRPCServer struct {
+	productpb.UnimplementedProductCatalogServer
+	svc *Service
+}
+
+// NewGRPCServer - Constructor for GRPCServer.
+func NewGRPCServer(svc *Service) *GRPCServer {
+	return &GRPCServer{svc: svc}
+}
+
+func toPB(p *Product) *productpb.Product {
+	return &productpb.Product{
+		Id:       int32(p.ID),
+		Name:     p.Name,
+		Price:    p.Price,
+		Quantity: int32(p.Quantity),
+		Category: p.Category,
+	}
+}
+
+func fromPB(p *productpb.Product) Product {
+	return Product{
+		ID:       int(p.GetId()),
+		Name:     p.GetName(),
+		Price:    p.GetPrice(),
+		Quantity: int(p.GetQuantity()),
+		Category: p.GetCategory(),
+	}
+}
+
+func grpcError(err error) error {
+	switch {
+	case errors.Is(err, ErrProductNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, ErrProductAlreadyExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// CreateProduct implements productpb.ProductCatalogServer.
+func (g *GRPCServer) CreateProduct(ctx context.Context, req *productpb.CreateProductRequest) (*productpb.CreateProductResponse, error) {
+	p, err := g.svc.CreateProduct(ctx, fromPB(req.GetProduct()))
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &productpb.CreateProductResponse{Product: toPB(p)}, nil
+}
+
+// GetProduct implements productpb.ProductCatalogServer.
+func (g *GRPCServer) GetProduct(ctx context.Context, req *productpb.GetProductRequest) (*productpb.GetProductResponse, error) {
+	p, err := g.svc.GetProduct(ctx, int(req.GetId()))
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &productpb.GetProductResponse{Product: toPB(p)}, nil
+}
+
+// UpdateProduct implements productpb.ProductCatalogServer.
+func (g *GRPCServer) UpdateProduct(ctx context.Context, req *productpb.UpdateProductRequest) (*productpb.UpdateProductResponse, error) {
+	p, err := g.svc.UpdateProduct(ctx, int(req.GetId()), fromPB(req.GetProduct()))
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &productpb.UpdateProductResponse{Product: toPB(p)}, nil
+}
+
+// DeleteProduct implements productpb.ProductCatalogServer.
+func (g *GRPCServer) DeleteProduct(ctx context.Context, req *productpb.DeleteProductRequest) (*productpb.DeleteProductResponse, error) {
+	if err := g.svc.DeleteProduct(ctx, int(req.GetId())); err != nil {
+		return nil, grpcError(err)
+	}
+	return &productpb.DeleteProductResponse{}, nil
+}
+
+// ListProducts implements productpb.ProductCatalogServer.
+func (g *GRPCServer) ListProducts(ctx context.Context, req *productpb.ListProductsRequest) (*productpb.ListProductsResponse, error) {
+	products, err := g.svc.ListProducts(ctx)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	pbProducts := make([]*productpb.Product, 0, len(products))
+	for i := range products {
+		pbProducts = append(pbProducts, toPB(&products[i]))
+	}
+	return &productpb.ListProductsResponse{Products: pbProducts}, nil
+}
@@ -0,0 +1,85 @@
+package product
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"repo/product/productpb"
+)
+
+// dialGRPCServer spins up GRPCServer over an in-memory bufconn listener and
+// returns a connected client. This exercises the real proto wire encoding
+// end to end, unlike a direct method call on GRPCServer.
+func dialGRPCServer(t *testing.T, svc *Service) productpb.ProductCatalogClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	productpb.RegisterProductCatalogServer(srv, NewGRPCServer(svc))
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return productpb.NewProductCatalogClient(conn)
+}
+
+func newTestService(t *testing.T) *Service {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&Product{}, &OutboxEvent{}))
+
+	return NewService(db)
+}
+
+func TestGRPCServer_CreateGetUpdateDeleteProduct_RoundTrip(t *testing.T) {
+	client := dialGRPCServer(t, newTestService(t))
+	ctx := context.Background()
+
+	created, err := client.CreateProduct(ctx, &productpb.CreateProductRequest{
+		Product: &productpb.Product{Id: 1, Name: "Widget", Price: 9.99, Quantity: 10, Category: "tools"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Widget", created.GetProduct().GetName())
+
+	got, err := client.GetProduct(ctx, &productpb.GetProductRequest{Id: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int32(10), got.GetProduct().GetQuantity())
+
+	updated, err := client.UpdateProduct(ctx, &productpb.UpdateProductRequest{
+		Id:      1,
+		Product: &productpb.Product{Name: "Widget", Price: 12.50, Quantity: 5, Category: "tools"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 12.50, updated.GetProduct().GetPrice())
+
+	list, err := client.ListProducts(ctx, &productpb.ListProductsRequest{})
+	require.NoError(t, err)
+	assert.Len(t, list.GetProducts(), 1)
+
+	_, err = client.DeleteProduct(ctx, &productpb.DeleteProductRequest{Id: 1})
+	require.NoError(t, err)
+
+	_, err = client.GetProduct(ctx, &productpb.GetProductRequest{Id: 1})
+	assert.Error(t, err)
+}
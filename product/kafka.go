@@ -0,0 +1,14 @@
+package product
+
+import (
+	"context"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaWriter is the subset of *kafka.Writer used by this codebase's event
+// publishers. It lets tests substitute a fake in place of a real broker
+// connection; *kafka.Writer satisfies it with no changes.
+type KafkaWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
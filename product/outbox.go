@@ -0,0 +1,112 @@
+package product
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"gorm.io/gorm"
+)
+
+// maxOutboxBackoff caps the exponential backoff applied to a repeatedly
+// failing outbox row so a dead broker doesn't push retries out for days.
+const maxOutboxBackoff = 5 * time.Minute
+
+// OutboxEvent is a row in the transactional outbox: it is written in the
+// same GORM transaction as the product change it describes, so a crash
+// between the Postgres write and the Kafka publish can't silently drop
+// the event the way writing to Kafka directly could.
+type OutboxEvent struct {
+	ID            uint   `gorm:"primaryKey"`
+	EventType     string `gorm:"column:event_type"`
+	Payload       string `gorm:"column:payload"`
+	Attempts      int    `gorm:"column:attempts"`
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	PublishedAt   *time.Time
+}
+
+// TableName overrides GORM's pluralized default.
+func (OutboxEvent) TableName() string {
+	return "outbox_events"
+}
+
+// enqueueOutboxEvent inserts an outbox row for event on tx, so it is
+// committed atomically with whatever product change tx also contains.
+func enqueueOutboxEvent(tx *gorm.DB, eventType string, event interface{}) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return tx.Create(&OutboxEvent{
+		EventType:     eventType,
+		Payload:       string(payload),
+		NextAttemptAt: time.Now(),
+	}).Error
+}
+
+// OutboxPublisher polls unpublished outbox rows and writes them to Kafka,
+// retrying failures with exponential backoff instead of losing them.
+type OutboxPublisher struct {
+	db     *gorm.DB
+	writer KafkaWriter
+}
+
+// NewOutboxPublisher - Constructor for OutboxPublisher.
+func NewOutboxPublisher(db *gorm.DB, writer KafkaWriter) *OutboxPublisher {
+	return &OutboxPublisher{db: db, writer: writer}
+}
+
+// PublishPending writes every due, unpublished outbox row to Kafka. The
+// Kafka message key carries the event type so consumers can route on it
+// without parsing the payload.
+func (p *OutboxPublisher) PublishPending(ctx context.Context) error {
+	var events []OutboxEvent
+	err := p.db.Where("published_at IS NULL AND next_attempt_at <= ?", time.Now()).
+		Order("created_at").Find(&events).Error
+	if err != nil {
+		return err
+	}
+
+	for i := range events {
+		event := &events[i]
+		writeErr := p.writer.WriteMessages(ctx, kafka.Message{
+			Key:   []byte(event.EventType),
+			Value: []byte(event.Payload),
+		})
+		if writeErr != nil {
+			event.Attempts++
+			backoff := time.Duration(1<<uint(event.Attempts)) * time.Second
+			if backoff > maxOutboxBackoff {
+				backoff = maxOutboxBackoff
+			}
+			p.db.Model(event).Updates(map[string]interface{}{
+				"attempts":        event.Attempts,
+				"next_attempt_at": time.Now().Add(backoff),
+			})
+			continue
+		}
+
+		now := time.Now()
+		p.db.Model(event).Update("published_at", &now)
+	}
+	return nil
+}
+
+// Run polls PublishPending on a fixed interval until ctx is cancelled.
+func (p *OutboxPublisher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.PublishPending(ctx); err != nil {
+				log.Printf("outbox publisher: %v", err)
+			}
+		}
+	}
+}
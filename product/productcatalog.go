@@ -17,13 +17,15 @@ var (
 	ErrFailedToGetProduct          = errors.New("failed to get product")
 	ErrFailedToDeleteProduct       = errors.New("failed to delete product")
 	ErrFailedToFetchProductDetails = errors.New("failed to fetch product details")
+	ErrInvalidQuantity             = errors.New("quantity must be positive")
 )
 
-// ProductStorage interface - Defines the methods for saving, retrieving, and deleting products.
+// ProductStorage interface - Defines the methods for saving, retrieving, listing, and deleting products.
 type ProductStorage interface {
 	Save(p Product) error
 	GetByID(id int) (*Product, error)
 	Delete(id int) error
+	List() ([]Product, error)
 }
 
 // Product struct
@@ -0,0 +1,219 @@
+package product
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrReservationNotFound = errors.New("reservation not found")
+	ErrReservationExpired  = errors.New("reservation expired")
+)
+
+// ReservationStatus tracks the lifecycle of a stock hold.
+type ReservationStatus string
+
+const (
+	ReservationHeld      ReservationStatus = "held"
+	ReservationCommitted ReservationStatus = "committed"
+	ReservationReleased  ReservationStatus = "released"
+	ReservationExpired   ReservationStatus = "expired"
+)
+
+// Reservation is a GORM-persisted hold against a product's stock, keyed by
+// a caller-supplied secret so the same caller can later commit or release it.
+type Reservation struct {
+	ID         uint              `gorm:"primaryKey"`
+	ProductID  int               `gorm:"column:product_id;index"`
+	Quantity   int               `gorm:"column:quantity"`
+	HoldSecret string            `gorm:"column:hold_secret;index"`
+	Status     ReservationStatus `gorm:"column:status"`
+	ExpiresAt  time.Time         `gorm:"column:expires_at"`
+}
+
+// TableName overrides GORM's pluralized default.
+func (Reservation) TableName() string {
+	return "reservations"
+}
+
+// ReservationEvent is published to the product-events Kafka topic once a
+// reservation is committed into a permanent stock decrement.
+type ReservationEvent struct {
+	ProductID int    `json:"product_id"`
+	Quantity  int    `json:"quantity"`
+	Status    string `json:"status"`
+}
+
+// ReservationStore implements the reserve/commit/release stock-hold
+// workflow on top of Postgres, guarding the decrement with a row lock so
+// concurrent checkouts can't oversell.
+type ReservationStore struct {
+	db     *gorm.DB
+	writer KafkaWriter
+	cache  ProductCache
+}
+
+// NewReservationStore - Constructor for ReservationStore.
+func NewReservationStore(db *gorm.DB, writer KafkaWriter, cache ProductCache) *ReservationStore {
+	return &ReservationStore{db: db, writer: writer, cache: cache}
+}
+
+// Reserve atomically decrements a product's available quantity and records
+// a hold against holdSecret that expires after ttl unless committed or
+// released first.
+func (s *ReservationStore) Reserve(productID int, qty int, holdSecret string, ttl time.Duration) (*Reservation, error) {
+	if qty <= 0 {
+		return nil, ErrInvalidQuantity
+	}
+
+	var reservation Reservation
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var p Product
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", productID).First(&p).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrProductNotFound
+			}
+			return err
+		}
+		if p.Quantity < qty {
+			return ErrInsufficientStock
+		}
+		p.Quantity -= qty
+		if err := tx.Save(&p).Error; err != nil {
+			return err
+		}
+
+		reservation = Reservation{
+			ProductID:  productID,
+			Quantity:   qty,
+			HoldSecret: holdSecret,
+			Status:     ReservationHeld,
+			ExpiresAt:  time.Now().Add(ttl),
+		}
+		return tx.Create(&reservation).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The transaction above decremented Product.Quantity directly, so the
+	// cache must be dropped or a GET could keep serving the pre-reservation
+	// quantity until the TTL expires. The reservation itself already
+	// committed, so a transient invalidation failure here is logged rather
+	// than failing the call: Reserve has no idempotency key, and a client
+	// retrying a reported failure with the same hold_secret would create a
+	// second reservation and double-decrement stock.
+	if err := s.cache.Invalidate(productID); err != nil {
+		log.Printf("reservation store: cache invalidation failed for product %d: %v", productID, err)
+	}
+	return &reservation, nil
+}
+
+// Commit consumes a held reservation, making its decrement permanent and
+// emitting an order_committed event.
+func (s *ReservationStore) Commit(ctx context.Context, productID int, holdSecret string) error {
+	reservation, err := s.findHeld(productID, holdSecret)
+	if err != nil {
+		return err
+	}
+
+	if err := s.db.Model(reservation).Update("status", ReservationCommitted).Error; err != nil {
+		return err
+	}
+
+	return s.publish(ctx, ReservationEvent{
+		ProductID: reservation.ProductID,
+		Quantity:  reservation.Quantity,
+		Status:    "order_committed",
+	})
+}
+
+// Release returns a held reservation's units back to available stock.
+func (s *ReservationStore) Release(productID int, holdSecret string) error {
+	reservation, err := s.findHeld(productID, holdSecret)
+	if err != nil {
+		return err
+	}
+	return s.release(reservation, ReservationReleased)
+}
+
+func (s *ReservationStore) findHeld(productID int, holdSecret string) (*Reservation, error) {
+	var reservation Reservation
+	err := s.db.Where("product_id = ? AND hold_secret = ? AND status = ?", productID, holdSecret, ReservationHeld).
+		First(&reservation).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReservationNotFound
+		}
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+func (s *ReservationStore) release(reservation *Reservation, status ReservationStatus) error {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&Product{}).Where("id = ?", reservation.ProductID).
+			Update("quantity", gorm.Expr("quantity + ?", reservation.Quantity)).Error; err != nil {
+			return err
+		}
+		return tx.Model(reservation).Update("status", status).Error
+	})
+	if err != nil {
+		return err
+	}
+	// Returning units to stock changes Product.Quantity directly; drop the
+	// cache entry so it doesn't keep serving the pre-release quantity. The
+	// release itself already committed, so log rather than fail the call on
+	// a transient invalidation error, same reasoning as in Reserve.
+	if err := s.cache.Invalidate(reservation.ProductID); err != nil {
+		log.Printf("reservation store: cache invalidation failed for product %d: %v", reservation.ProductID, err)
+	}
+	return nil
+}
+
+// SweepExpired releases every held reservation whose TTL has passed. It is
+// intended to be called periodically by a background goroutine.
+func (s *ReservationStore) SweepExpired() error {
+	var expired []Reservation
+	if err := s.db.Where("status = ? AND expires_at < ?", ReservationHeld, time.Now()).Find(&expired).Error; err != nil {
+		return err
+	}
+	for i := range expired {
+		if err := s.release(&expired[i], ReservationExpired); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunSweeper runs SweepExpired on a fixed interval until ctx is cancelled,
+// logging (rather than failing) individual sweep errors so one bad pass
+// doesn't stop future holds from being released.
+func (s *ReservationStore) RunSweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.SweepExpired(); err != nil {
+				log.Printf("reservation sweeper: %v", err)
+			}
+		}
+	}
+}
+
+func (s *ReservationStore) publish(ctx context.Context, event ReservationEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return s.writer.WriteMessages(ctx, kafka.Message{Value: payload})
+}
@@ -0,0 +1,189 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// fakeWriter records every message it's asked to write, instead of
+// requiring a live Kafka broker.
+type fakeWriter struct {
+	messages []kafka.Message
+}
+
+func (w *fakeWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	w.messages = append(w.messages, msgs...)
+	return nil
+}
+
+// failingInvalidateCache wraps a ProductCache but always fails Invalidate,
+// to exercise the log-and-continue handling of a transient cache-invalidation
+// error after a reservation mutation has already committed.
+type failingInvalidateCache struct {
+	ProductCache
+}
+
+func (c *failingInvalidateCache) Invalidate(id int) error {
+	return errors.New("cache unreachable")
+}
+
+func newTestReservationStore(t *testing.T) (*ReservationStore, *gorm.DB, *fakeWriter) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&Product{}, &Reservation{}))
+	require.NoError(t, db.Create(&Product{ID: 1, Name: "Widget", Price: 5, Quantity: 10}).Error)
+
+	writer := &fakeWriter{}
+	cache := NewNullCache(NewGormStorage(db))
+	return NewReservationStore(db, writer, cache), db, writer
+}
+
+func TestReservationStore_Reserve_DecrementsStock(t *testing.T) {
+	store, db, _ := newTestReservationStore(t)
+
+	reservation, err := store.Reserve(1, 4, "secret-1", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, ReservationHeld, reservation.Status)
+
+	var p Product
+	require.NoError(t, db.First(&p, 1).Error)
+	assert.Equal(t, 6, p.Quantity)
+}
+
+func TestReservationStore_Reserve_InsufficientStock(t *testing.T) {
+	store, _, _ := newTestReservationStore(t)
+
+	_, err := store.Reserve(1, 11, "secret-1", time.Minute)
+	assert.ErrorIs(t, err, ErrInsufficientStock)
+}
+
+func TestReservationStore_Reserve_ProductNotFound(t *testing.T) {
+	store, _, _ := newTestReservationStore(t)
+
+	_, err := store.Reserve(999, 1, "secret-1", time.Minute)
+	assert.ErrorIs(t, err, ErrProductNotFound)
+}
+
+func TestReservationStore_Reserve_RejectsNonPositiveQuantity(t *testing.T) {
+	store, db, _ := newTestReservationStore(t)
+
+	_, err := store.Reserve(1, -50, "secret-1", time.Minute)
+	assert.ErrorIs(t, err, ErrInvalidQuantity)
+
+	_, err = store.Reserve(1, 0, "secret-2", time.Minute)
+	assert.ErrorIs(t, err, ErrInvalidQuantity)
+
+	// Stock must be untouched: a negative quantity must never inflate it,
+	// and no reservation row should have been created either.
+	var p Product
+	require.NoError(t, db.First(&p, 1).Error)
+	assert.Equal(t, 10, p.Quantity)
+
+	var count int64
+	require.NoError(t, db.Model(&Reservation{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestReservationStore_Reserve_SucceedsDespiteCacheInvalidationFailure(t *testing.T) {
+	store, db, _ := newTestReservationStore(t)
+	store.cache = &failingInvalidateCache{ProductCache: store.cache}
+
+	reservation, err := store.Reserve(1, 4, "secret-1", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, ReservationHeld, reservation.Status)
+
+	var p Product
+	require.NoError(t, db.First(&p, 1).Error)
+	assert.Equal(t, 6, p.Quantity)
+}
+
+func TestReservationStore_Release_SucceedsDespiteCacheInvalidationFailure(t *testing.T) {
+	store, db, _ := newTestReservationStore(t)
+
+	_, err := store.Reserve(1, 4, "secret-1", time.Minute)
+	require.NoError(t, err)
+
+	store.cache = &failingInvalidateCache{ProductCache: store.cache}
+	require.NoError(t, store.Release(1, "secret-1"))
+
+	var p Product
+	require.NoError(t, db.First(&p, 1).Error)
+	assert.Equal(t, 10, p.Quantity)
+}
+
+func TestReservationStore_Commit_MarksCommitted(t *testing.T) {
+	store, db, writer := newTestReservationStore(t)
+
+	_, err := store.Reserve(1, 4, "secret-1", time.Minute)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Commit(context.Background(), 1, "secret-1"))
+
+	var reservation Reservation
+	require.NoError(t, db.Where("product_id = ? AND hold_secret = ?", 1, "secret-1").First(&reservation).Error)
+	assert.Equal(t, ReservationCommitted, reservation.Status)
+	assert.Len(t, writer.messages, 1)
+
+	// Committing doesn't return stock, so quantity stays decremented.
+	var p Product
+	require.NoError(t, db.First(&p, 1).Error)
+	assert.Equal(t, 6, p.Quantity)
+}
+
+func TestReservationStore_Commit_NotFound(t *testing.T) {
+	store, _, _ := newTestReservationStore(t)
+
+	err := store.Commit(context.Background(), 1, "no-such-secret")
+	assert.ErrorIs(t, err, ErrReservationNotFound)
+}
+
+func TestReservationStore_Release_ReturnsStock(t *testing.T) {
+	store, db, _ := newTestReservationStore(t)
+
+	_, err := store.Reserve(1, 4, "secret-1", time.Minute)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Release(1, "secret-1"))
+
+	var p Product
+	require.NoError(t, db.First(&p, 1).Error)
+	assert.Equal(t, 10, p.Quantity)
+
+	var reservation Reservation
+	require.NoError(t, db.Where("product_id = ? AND hold_secret = ?", 1, "secret-1").First(&reservation).Error)
+	assert.Equal(t, ReservationReleased, reservation.Status)
+}
+
+func TestReservationStore_SweepExpired_ReleasesOnlyExpiredHolds(t *testing.T) {
+	store, db, _ := newTestReservationStore(t)
+
+	_, err := store.Reserve(1, 3, "expired-secret", -time.Minute)
+	require.NoError(t, err)
+	_, err = store.Reserve(1, 2, "live-secret", time.Hour)
+	require.NoError(t, err)
+
+	require.NoError(t, store.SweepExpired())
+
+	var expired Reservation
+	require.NoError(t, db.Where("hold_secret = ?", "expired-secret").First(&expired).Error)
+	assert.Equal(t, ReservationExpired, expired.Status)
+
+	var live Reservation
+	require.NoError(t, db.Where("hold_secret = ?", "live-secret").First(&live).Error)
+	assert.Equal(t, ReservationHeld, live.Status)
+
+	var p Product
+	require.NoError(t, db.First(&p, 1).Error)
+	// 10 - 3 - 2 + 3 (expired hold returned) = 8
+	assert.Equal(t, 8, p.Quantity)
+}
@@ -0,0 +1,148 @@
+package product
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProductEvent is the payload of every outbox row this service writes; it
+// is eventually delivered to the product-events Kafka topic by an
+// OutboxPublisher.
+type ProductEvent struct {
+	ProductID int    `json:"product_id"`
+	Quantity  int    `json:"quantity"`
+	Status    string `json:"status"`
+}
+
+// Service implements the product CRUD use cases against Postgres (via
+// GORM). Every mutation writes its event into the transactional outbox in
+// the same transaction as the row change, so a crash between the two can't
+// silently drop the event the way writing to Kafka directly could. It is
+// the shared business logic behind both the REST handlers and the gRPC
+// server, so the two entrypoints stay thin adapters rather than
+// duplicating validation and event-publishing logic.
+type Service struct {
+	db *gorm.DB
+}
+
+// NewService - Constructor for Service.
+func NewService(db *gorm.DB) *Service {
+	return &Service{db: db}
+}
+
+// CreateProduct saves a new product, rejecting duplicates by ID.
+func (s *Service) CreateProduct(ctx context.Context, p Product) (*Product, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var existing Product
+		if err := tx.Where("id = ?", p.ID).First(&existing).Error; err == nil {
+			return ErrProductAlreadyExists
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if err := tx.Create(&p).Error; err != nil {
+			return err
+		}
+
+		return enqueueOutboxEvent(tx, "created", ProductEvent{ProductID: p.ID, Quantity: p.Quantity, Status: "created"})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// GetProduct retrieves a product by ID.
+func (s *Service) GetProduct(ctx context.Context, id int) (*Product, error) {
+	var p Product
+	if err := s.db.Where("id = ?", id).First(&p).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
+// UpdateProduct overwrites the mutable fields of an existing product.
+func (s *Service) UpdateProduct(ctx context.Context, id int, updated Product) (*Product, error) {
+	var p Product
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", id).First(&p).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrProductNotFound
+			}
+			return err
+		}
+		p.Name = updated.Name
+		p.Price = updated.Price
+		p.Quantity = updated.Quantity
+		p.Category = updated.Category
+
+		if err := tx.Save(&p).Error; err != nil {
+			return err
+		}
+
+		return enqueueOutboxEvent(tx, "updated", ProductEvent{ProductID: p.ID, Quantity: p.Quantity, Status: "updated"})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// DeleteProduct removes a product by ID.
+func (s *Service) DeleteProduct(ctx context.Context, id int) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Delete(&Product{}, id)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrProductNotFound
+		}
+		return enqueueOutboxEvent(tx, "deleted", ProductEvent{ProductID: id, Quantity: 0, Status: "deleted"})
+	})
+}
+
+// Consume decrements a product's quantity inside a row-locked transaction
+// so concurrent consumers can't oversell, returning ErrInsufficientStock
+// if the requested quantity isn't available.
+func (s *Service) Consume(ctx context.Context, id int, quantity int) (*Product, error) {
+	if quantity <= 0 {
+		return nil, ErrInvalidQuantity
+	}
+
+	var p Product
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&p).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrProductNotFound
+			}
+			return err
+		}
+		if err := p.Sell(quantity); err != nil {
+			return err
+		}
+		if err := tx.Save(&p).Error; err != nil {
+			return err
+		}
+		return enqueueOutboxEvent(tx, "consumed", ProductEvent{ProductID: p.ID, Quantity: p.Quantity, Status: "consumed"})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// ListProducts returns every product in the catalog.
+func (s *Service) ListProducts(ctx context.Context) ([]Product, error) {
+	var products []Product
+	if err := s.db.Find(&products).Error; err != nil {
+		return nil, err
+	}
+	return products, nil
+}
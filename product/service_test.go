@@ -0,0 +1,49 @@
+package product
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func newTestProductService(t *testing.T) (*Service, *gorm.DB) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&Product{}, &OutboxEvent{}))
+	require.NoError(t, db.Create(&Product{ID: 1, Name: "Widget", Price: 5, Quantity: 10}).Error)
+
+	return NewService(db), db
+}
+
+func TestService_Consume_DecrementsStock(t *testing.T) {
+	svc, db := newTestProductService(t)
+
+	got, err := svc.Consume(context.Background(), 1, 4)
+	require.NoError(t, err)
+	assert.Equal(t, 6, got.Quantity)
+
+	var p Product
+	require.NoError(t, db.First(&p, 1).Error)
+	assert.Equal(t, 6, p.Quantity)
+}
+
+func TestService_Consume_RejectsNonPositiveQuantity(t *testing.T) {
+	svc, db := newTestProductService(t)
+
+	_, err := svc.Consume(context.Background(), 1, -50)
+	assert.ErrorIs(t, err, ErrInvalidQuantity)
+
+	_, err = svc.Consume(context.Background(), 1, 0)
+	assert.ErrorIs(t, err, ErrInvalidQuantity)
+
+	// Stock must be untouched: a negative quantity must never inflate it.
+	var p Product
+	require.NoError(t, db.First(&p, 1).Error)
+	assert.Equal(t, 10, p.Quantity)
+}
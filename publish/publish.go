@@ -0,0 +1,249 @@
+// Package publish adds draft/scheduled/online staging on top of
+// productcatalog.Product so catalog editors can prepare price/quantity
+// changes without affecting the live product until they publish them.
+package publish
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	productcatalog "repo/product"
+
+	"github.com/segmentio/kafka-go"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrVersionNotFound  = errors.New("product version not found")
+	ErrVersionNotDraft  = errors.New("product version is not a draft")
+	ErrVersionNotOnline = errors.New("product version is not online")
+)
+
+// Status is the lifecycle state of a Version.
+type Status string
+
+const (
+	StatusDraft   Status = "draft"
+	StatusOnline  Status = "online"
+	StatusOffline Status = "offline"
+)
+
+// Version is a snapshot of a product's editable fields, staged under a
+// named version until it is published onto the live Product row.
+type Version struct {
+	ID             uint       `gorm:"primaryKey"`
+	ProductID      int        `gorm:"column:product_id;index"`
+	Name           string     `gorm:"column:name;index"`
+	Status         Status     `gorm:"column:status"`
+	ScheduledStart *time.Time `gorm:"column:scheduled_start"`
+	ScheduledEnd   *time.Time `gorm:"column:scheduled_end"`
+	ProductName    string     `gorm:"column:product_name"`
+	Price          float64    `gorm:"column:price"`
+	Quantity       int        `gorm:"column:quantity"`
+	Category       string     `gorm:"column:category"`
+	CreatedAt      time.Time
+}
+
+// TableName overrides GORM's pluralized default.
+func (Version) TableName() string {
+	return "product_versions"
+}
+
+// Event is published to the product-events Kafka topic when a version
+// transitions online or offline.
+type Event struct {
+	ProductID int    `json:"product_id"`
+	Version   string `json:"version"`
+	Status    string `json:"status"`
+}
+
+// Service manages product versions against Postgres and keeps the live
+// productcatalog.Product row in sync with whichever version is online.
+type Service struct {
+	db     *gorm.DB
+	writer productcatalog.KafkaWriter
+	cache  productcatalog.ProductCache
+}
+
+// NewService - Constructor for Service.
+func NewService(db *gorm.DB, writer productcatalog.KafkaWriter, cache productcatalog.ProductCache) *Service {
+	return &Service{db: db, writer: writer, cache: cache}
+}
+
+// CreateDraft stages a new draft version named versionName, seeded from the
+// product's current live fields.
+func (s *Service) CreateDraft(productID int, versionName string) (*Version, error) {
+	var p productcatalog.Product
+	if err := s.db.Where("id = ?", productID).First(&p).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, productcatalog.ErrProductNotFound
+		}
+		return nil, err
+	}
+
+	version := Version{
+		ProductID:   productID,
+		Name:        versionName,
+		Status:      StatusDraft,
+		ProductName: p.Name,
+		Price:       p.Price,
+		Quantity:    p.Quantity,
+		Category:    p.Category,
+	}
+	if err := s.db.Create(&version).Error; err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// GetVersion returns a single named version of a product.
+func (s *Service) GetVersion(productID int, versionName string) (*Version, error) {
+	var version Version
+	err := s.db.Where("product_id = ? AND name = ?", productID, versionName).First(&version).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVersionNotFound
+		}
+		return nil, err
+	}
+	return &version, nil
+}
+
+// Publish marks a draft version online, immediately if scheduledStart is
+// nil or already due, or at scheduledStart otherwise (the background
+// scheduler performs the actual flip in that case). scheduledEnd, if set,
+// is carried onto the version so SweepSchedule can later offline it
+// automatically once it expires.
+func (s *Service) Publish(ctx context.Context, productID int, versionName string, scheduledStart, scheduledEnd *time.Time) error {
+	version, err := s.GetVersion(productID, versionName)
+	if err != nil {
+		return err
+	}
+	if version.Status != StatusDraft {
+		return ErrVersionNotDraft
+	}
+	version.ScheduledEnd = scheduledEnd
+
+	if scheduledStart != nil && scheduledStart.After(time.Now()) {
+		return s.db.Model(version).Updates(map[string]interface{}{
+			"scheduled_start": scheduledStart,
+			"scheduled_end":   scheduledEnd,
+		}).Error
+	}
+
+	return s.publishNow(ctx, version)
+}
+
+// Unpublish marks an online version offline without affecting the live
+// Product row's current fields.
+func (s *Service) Unpublish(ctx context.Context, productID int, versionName string) error {
+	version, err := s.GetVersion(productID, versionName)
+	if err != nil {
+		return err
+	}
+	if version.Status != StatusOnline {
+		return ErrVersionNotOnline
+	}
+
+	if err := s.db.Model(version).Update("status", StatusOffline).Error; err != nil {
+		return err
+	}
+	return s.publishEvent(ctx, Event{ProductID: productID, Version: versionName, Status: "unpublished"})
+}
+
+// SweepSchedule publishes any draft whose scheduled start has arrived and
+// offlines any online version whose scheduled end has passed. It is
+// intended to be called periodically by a background goroutine.
+func (s *Service) SweepSchedule(ctx context.Context) error {
+	now := time.Now()
+
+	var due []Version
+	if err := s.db.Where("status = ? AND scheduled_start IS NOT NULL AND scheduled_start <= ?", StatusDraft, now).
+		Find(&due).Error; err != nil {
+		return err
+	}
+	for i := range due {
+		if err := s.publishNow(ctx, &due[i]); err != nil {
+			return err
+		}
+	}
+
+	var expired []Version
+	if err := s.db.Where("status = ? AND scheduled_end IS NOT NULL AND scheduled_end <= ?", StatusOnline, now).
+		Find(&expired).Error; err != nil {
+		return err
+	}
+	for i := range expired {
+		if err := s.db.Model(&expired[i]).Update("status", StatusOffline).Error; err != nil {
+			return err
+		}
+		if err := s.publishEvent(ctx, Event{ProductID: expired[i].ProductID, Version: expired[i].Name, Status: "unpublished"}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunScheduler runs SweepSchedule on a fixed interval until ctx is cancelled.
+func (s *Service) RunScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = s.SweepSchedule(ctx)
+		}
+	}
+}
+
+func (s *Service) publishNow(ctx context.Context, version *Version) error {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&Version{}).
+			Where("product_id = ? AND status = ?", version.ProductID, StatusOnline).
+			Update("status", StatusOffline).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(version).Updates(map[string]interface{}{
+			"status":          StatusOnline,
+			"scheduled_start": nil,
+			"scheduled_end":   version.ScheduledEnd,
+		}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&productcatalog.Product{}).Where("id = ?", version.ProductID).Updates(map[string]interface{}{
+			"name":     version.ProductName,
+			"price":    version.Price,
+			"quantity": version.Quantity,
+			"category": version.Category,
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	// The transaction above wrote the canonical Product row directly, so
+	// the cache must be dropped or a GET could keep serving the
+	// pre-publish row for up to its TTL. The publish itself already
+	// committed, so a transient invalidation failure is logged rather than
+	// failing the call, same reasoning as product.ReservationStore.
+	if err := s.cache.Invalidate(version.ProductID); err != nil {
+		log.Printf("publish: cache invalidation failed for product %d: %v", version.ProductID, err)
+	}
+
+	return s.publishEvent(ctx, Event{ProductID: version.ProductID, Version: version.Name, Status: "published"})
+}
+
+func (s *Service) publishEvent(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return s.writer.WriteMessages(ctx, kafka.Message{Value: payload})
+}
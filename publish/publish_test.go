@@ -0,0 +1,210 @@
+package publish
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	productcatalog "repo/product"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// fakeWriter records every message it's asked to write, instead of
+// requiring a live Kafka broker.
+type fakeWriter struct {
+	messages []kafka.Message
+}
+
+func (w *fakeWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	w.messages = append(w.messages, msgs...)
+	return nil
+}
+
+// failingInvalidateCache wraps a ProductCache but always fails Invalidate,
+// to exercise the log-and-continue handling of a transient cache-invalidation
+// error after a publish has already committed.
+type failingInvalidateCache struct {
+	productcatalog.ProductCache
+}
+
+func (c *failingInvalidateCache) Invalidate(id int) error {
+	return errors.New("cache unreachable")
+}
+
+func newTestService(t *testing.T) (*Service, *gorm.DB, *fakeWriter) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&productcatalog.Product{}, &Version{}))
+	require.NoError(t, db.Create(&productcatalog.Product{ID: 1, Name: "Widget", Price: 5, Quantity: 10}).Error)
+
+	writer := &fakeWriter{}
+	cache := productcatalog.NewNullCache(productcatalog.NewGormStorage(db))
+	return NewService(db, writer, cache), db, writer
+}
+
+func TestService_CreateDraft_SeedsFromLiveProduct(t *testing.T) {
+	svc, _, _ := newTestService(t)
+
+	version, err := svc.CreateDraft(1, "v1")
+	require.NoError(t, err)
+	assert.Equal(t, StatusDraft, version.Status)
+	assert.Equal(t, "Widget", version.ProductName)
+}
+
+func TestService_CreateDraft_ProductNotFound(t *testing.T) {
+	svc, _, _ := newTestService(t)
+
+	_, err := svc.CreateDraft(999, "v1")
+	assert.ErrorIs(t, err, productcatalog.ErrProductNotFound)
+}
+
+func TestService_GetVersion_NotFound(t *testing.T) {
+	svc, _, _ := newTestService(t)
+
+	_, err := svc.GetVersion(1, "no-such-version")
+	assert.ErrorIs(t, err, ErrVersionNotFound)
+}
+
+func TestService_Publish_Immediate(t *testing.T) {
+	svc, db, writer := newTestService(t)
+	ctx := context.Background()
+
+	version, err := svc.CreateDraft(1, "v1")
+	require.NoError(t, err)
+	require.NoError(t, db.Model(version).Update("price", 7.5).Error)
+
+	require.NoError(t, svc.Publish(ctx, 1, "v1", nil, nil))
+
+	got, err := svc.GetVersion(1, "v1")
+	require.NoError(t, err)
+	assert.Equal(t, StatusOnline, got.Status)
+
+	var p productcatalog.Product
+	require.NoError(t, db.First(&p, 1).Error)
+	assert.Equal(t, 7.5, p.Price)
+
+	assert.Len(t, writer.messages, 1)
+}
+
+func TestService_Publish_SucceedsDespiteCacheInvalidationFailure(t *testing.T) {
+	svc, _, writer := newTestService(t)
+	ctx := context.Background()
+	svc.cache = &failingInvalidateCache{ProductCache: svc.cache}
+
+	_, err := svc.CreateDraft(1, "v1")
+	require.NoError(t, err)
+
+	require.NoError(t, svc.Publish(ctx, 1, "v1", nil, nil))
+
+	got, err := svc.GetVersion(1, "v1")
+	require.NoError(t, err)
+	assert.Equal(t, StatusOnline, got.Status)
+	assert.Len(t, writer.messages, 1)
+}
+
+func TestService_Publish_NotDraft(t *testing.T) {
+	svc, _, _ := newTestService(t)
+	ctx := context.Background()
+
+	_, err := svc.CreateDraft(1, "v1")
+	require.NoError(t, err)
+	require.NoError(t, svc.Publish(ctx, 1, "v1", nil, nil))
+
+	err = svc.Publish(ctx, 1, "v1", nil, nil)
+	assert.ErrorIs(t, err, ErrVersionNotDraft)
+}
+
+func TestService_Publish_Deferred_DoesNotGoOnlineYet(t *testing.T) {
+	svc, _, writer := newTestService(t)
+	ctx := context.Background()
+
+	_, err := svc.CreateDraft(1, "v1")
+	require.NoError(t, err)
+
+	start := time.Now().Add(time.Hour)
+	end := time.Now().Add(2 * time.Hour)
+	require.NoError(t, svc.Publish(ctx, 1, "v1", &start, &end))
+
+	version, err := svc.GetVersion(1, "v1")
+	require.NoError(t, err)
+	assert.Equal(t, StatusDraft, version.Status)
+	require.NotNil(t, version.ScheduledStart)
+	require.NotNil(t, version.ScheduledEnd)
+	assert.Empty(t, writer.messages)
+}
+
+func TestService_Unpublish(t *testing.T) {
+	svc, _, writer := newTestService(t)
+	ctx := context.Background()
+
+	_, err := svc.CreateDraft(1, "v1")
+	require.NoError(t, err)
+	require.NoError(t, svc.Publish(ctx, 1, "v1", nil, nil))
+
+	require.NoError(t, svc.Unpublish(ctx, 1, "v1"))
+
+	version, err := svc.GetVersion(1, "v1")
+	require.NoError(t, err)
+	assert.Equal(t, StatusOffline, version.Status)
+	assert.Len(t, writer.messages, 2)
+}
+
+func TestService_Unpublish_NotOnline(t *testing.T) {
+	svc, _, _ := newTestService(t)
+
+	_, err := svc.CreateDraft(1, "v1")
+	require.NoError(t, err)
+
+	err = svc.Unpublish(context.Background(), 1, "v1")
+	assert.ErrorIs(t, err, ErrVersionNotOnline)
+}
+
+func TestService_SweepSchedule_PublishesDueDraft(t *testing.T) {
+	svc, db, writer := newTestService(t)
+	ctx := context.Background()
+
+	version, err := svc.CreateDraft(1, "v1")
+	require.NoError(t, err)
+	due := time.Now().Add(-time.Minute)
+	require.NoError(t, db.Model(version).Update("scheduled_start", &due).Error)
+
+	require.NoError(t, svc.SweepSchedule(ctx))
+
+	got, err := svc.GetVersion(1, "v1")
+	require.NoError(t, err)
+	assert.Equal(t, StatusOnline, got.Status)
+	assert.Len(t, writer.messages, 1)
+}
+
+func TestService_SweepSchedule_OfflinesExpiredOnline(t *testing.T) {
+	svc, _, writer := newTestService(t)
+	ctx := context.Background()
+
+	_, err := svc.CreateDraft(1, "v1")
+	require.NoError(t, err)
+	end := time.Now().Add(time.Minute)
+	require.NoError(t, svc.Publish(ctx, 1, "v1", nil, &end))
+
+	// scheduledEnd hasn't passed yet, so the first sweep should leave it online.
+	require.NoError(t, svc.SweepSchedule(ctx))
+	version, err := svc.GetVersion(1, "v1")
+	require.NoError(t, err)
+	assert.Equal(t, StatusOnline, version.Status)
+
+	expired := time.Now().Add(-time.Minute)
+	require.NoError(t, svc.db.Model(version).Update("scheduled_end", &expired).Error)
+
+	require.NoError(t, svc.SweepSchedule(ctx))
+	version, err = svc.GetVersion(1, "v1")
+	require.NoError(t, err)
+	assert.Equal(t, StatusOffline, version.Status)
+	assert.Len(t, writer.messages, 2)
+}